@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/TheAkif/mistake-logger/internal/middleware/accesslog"
+)
+
+// fileConfig is the optional on-disk config file, currently just the access
+// log settings. Anything not set there falls back to the accesslog package's
+// own defaults (combined format, stdout).
+type fileConfig struct {
+	AccessLog accesslog.Config `json:"access_log"`
+}
+
+// loadFileConfig reads path if it exists and returns its zero value
+// otherwise, so running without -config "just works".
+func loadFileConfig(path string) (fileConfig, error) {
+	var cfg fileConfig
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("read config %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
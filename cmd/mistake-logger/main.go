@@ -0,0 +1,46 @@
+// Command mistake-logger serves the mistake-logger web app: flag parsing and
+// process wiring only, everything else lives in internal/app.
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/TheAkif/mistake-logger/internal/app"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8080", "address to listen on")
+	dbPath := flag.String("db", "mistakes.db", "path to the sqlite database file")
+	templateDir := flag.String("templates", "templates", "directory of html templates")
+	staticDir := flag.String("static", "static", "directory of static assets")
+	configPath := flag.String("config", "config.json", "path to an optional JSON config file")
+	flag.Parse()
+
+	log := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	fileCfg, err := loadFileConfig(*configPath)
+	if err != nil {
+		log.Error("load config", "error", err)
+		os.Exit(1)
+	}
+
+	a, err := app.New(app.Config{
+		Addr:        *addr,
+		DBPath:      *dbPath,
+		TemplateDir: *templateDir,
+		StaticDir:   *staticDir,
+		AccessLog:   fileCfg.AccessLog,
+	}, log)
+	if err != nil {
+		log.Error("init app", "error", err)
+		os.Exit(1)
+	}
+	defer a.Close()
+
+	log.Info("listening", "addr", *addr)
+	log.Error("server exited", "error", http.ListenAndServe(*addr, a.Mux()))
+	os.Exit(1)
+}
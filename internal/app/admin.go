@@ -0,0 +1,116 @@
+package app
+
+import (
+	"net/http"
+	"time"
+)
+
+// mistakeCounts returns the total row count and a per-topic breakdown,
+// queried fresh each time so it stays correct regardless of who else is
+// writing to the table (used by both /admin and the Prometheus collector).
+func (a *App) mistakeCounts() (int, map[string]int, error) {
+	rows, err := a.db.Query(`SELECT topic, count(*) FROM mistakes GROUP BY topic;`)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	byTopic := make(map[string]int)
+	total := 0
+	for rows.Next() {
+		var topic string
+		var n int
+		if err := rows.Scan(&topic, &n); err != nil {
+			return 0, nil, err
+		}
+		byTopic[topic] = n
+		total += n
+	}
+	return total, byTopic, rows.Err()
+}
+
+// DailyCount is one point on the mistakes-added-per-day sparkline.
+type DailyCount struct {
+	Day   string
+	Count int
+}
+
+// dailyCounts returns one DailyCount per day for the last `days` days
+// (oldest first), zero-filled for days with no mistakes added.
+func (a *App) dailyCounts(days int) ([]DailyCount, error) {
+	since := time.Now().AddDate(0, 0, -days+1).Format("2006-01-02")
+
+	rows, err := a.db.Query(`
+SELECT substr(created_at, 1, 10) AS day, count(*)
+FROM mistakes
+WHERE substr(created_at, 1, 10) >= ?
+GROUP BY day;
+`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var day string
+		var n int
+		if err := rows.Scan(&day, &n); err != nil {
+			return nil, err
+		}
+		counts[day] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]DailyCount, days)
+	start := time.Now().AddDate(0, 0, -days+1)
+	for i := 0; i < days; i++ {
+		day := start.AddDate(0, 0, i).Format("2006-01-02")
+		out[i] = DailyCount{Day: day, Count: counts[day]}
+	}
+	return out, nil
+}
+
+// AdminData backs the /admin dashboard template.
+type AdminData struct {
+	User            string
+	Total           int
+	ByTopic         map[string]int
+	DailyCounts     []DailyCount
+	SlowestRequests []requestTiming
+}
+
+func (a *App) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	total, byTopic, err := a.mistakeCounts()
+	if err != nil {
+		http.Error(w, "Failed to load mistake stats", http.StatusInternalServerError)
+		a.log.Error("mistakeCounts", "error", err)
+		return
+	}
+
+	daily, err := a.dailyCounts(30)
+	if err != nil {
+		http.Error(w, "Failed to load daily stats", http.StatusInternalServerError)
+		a.log.Error("dailyCounts", "error", err)
+		return
+	}
+
+	data := AdminData{
+		User:            sessionUser(r),
+		Total:           total,
+		ByTopic:         byTopic,
+		DailyCounts:     daily,
+		SlowestRequests: a.requestRing.slowest(10),
+	}
+
+	if err := a.tmpl.ExecuteTemplate(w, "admin.html", data); err != nil {
+		a.log.Error("template execute admin", "error", err)
+	}
+}
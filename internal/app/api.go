@@ -0,0 +1,204 @@
+package app
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiError carries a machine-readable code alongside the message returned in
+// the JSON error body.
+type apiError struct {
+	Code    string
+	Message string
+}
+
+func (e apiError) Error() string { return e.Message }
+
+func newAPIError(code, message string) apiError {
+	return apiError{Code: code, Message: message}
+}
+
+// writeJSON encodes v as the response body, setting the given status first.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// apiHandler is the shape every /api/v1 handler implements: it writes its
+// own success body via writeJSON and returns the status purely for the
+// adapter's error path, or returns a status/error pair for adapt to render
+// as {"error":"...","code":"..."}.
+type apiHandler func(w http.ResponseWriter, r *http.Request) (int, error)
+
+// adapt turns an apiHandler into an http.HandlerFunc, rendering returned
+// errors as a JSON body instead of the plain-text http.Error the HTML
+// handlers use.
+func (a *App) adapt(h apiHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, err := h(w, r)
+		if err == nil {
+			return
+		}
+
+		code := "internal"
+		var apiErr apiError
+		if errors.As(err, &apiErr) {
+			code = apiErr.Code
+		}
+		a.log.Error("api error", "path", r.URL.Path, "status", status, "error", err)
+		writeJSON(w, status, map[string]string{"error": err.Error(), "code": code})
+	}
+}
+
+func (a *App) apiMistakesCollection(w http.ResponseWriter, r *http.Request) (int, error) {
+	switch r.Method {
+	case http.MethodGet:
+		q := strings.TrimSpace(r.URL.Query().Get("q"))
+		topic := strings.TrimSpace(r.URL.Query().Get("topic"))
+		from := strings.TrimSpace(r.URL.Query().Get("from"))
+		to := strings.TrimSpace(r.URL.Query().Get("to"))
+
+		items, err := a.searchMistakes(q, topic, from, to, 200)
+		if err != nil {
+			return http.StatusInternalServerError, newAPIError("internal", "failed to load mistakes")
+		}
+		writeJSON(w, http.StatusOK, items)
+		return http.StatusOK, nil
+
+	case http.MethodPost:
+		var body struct {
+			Topic           string `json:"topic"`
+			Date            string `json:"date"`
+			Problem         string `json:"problem_statement"`
+			Missed          string `json:"what_i_missed"`
+			FixRule         string `json:"fix_rule"`
+			PatternRemember string `json:"pattern_to_remember"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return http.StatusBadRequest, newAPIError("bad_request", "invalid JSON body")
+		}
+
+		m := Mistake{
+			Topic:           strings.TrimSpace(body.Topic),
+			Date:            strings.TrimSpace(body.Date),
+			Problem:         strings.TrimSpace(body.Problem),
+			Missed:          strings.TrimSpace(body.Missed),
+			FixRule:         strings.TrimSpace(body.FixRule),
+			PatternRemember: strings.TrimSpace(body.PatternRemember),
+		}
+		if m.Topic == "" || m.Date == "" || m.Problem == "" || m.Missed == "" || m.FixRule == "" || m.PatternRemember == "" {
+			return http.StatusBadRequest, newAPIError("bad_request", "all fields are required")
+		}
+		m.CreatedAt = time.Now().Format(time.RFC3339)
+
+		if err := a.insertMistake(m); err != nil {
+			return http.StatusInternalServerError, newAPIError("internal", "failed to save mistake")
+		}
+		writeJSON(w, http.StatusCreated, m)
+		return http.StatusCreated, nil
+
+	default:
+		return http.StatusMethodNotAllowed, newAPIError("method_not_allowed", "method not allowed")
+	}
+}
+
+func (a *App) apiMistakeByID(w http.ResponseWriter, r *http.Request) (int, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/api/v1/mistakes/"), 10, 64)
+	if err != nil || id <= 0 {
+		return http.StatusBadRequest, newAPIError("bad_request", "invalid mistake id")
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		m, err := a.getMistakeByID(id)
+		if err != nil {
+			return http.StatusNotFound, newAPIError("not_found", "mistake not found")
+		}
+		writeJSON(w, http.StatusOK, m)
+		return http.StatusOK, nil
+
+	case http.MethodPut:
+		var body struct {
+			Topic           string `json:"topic"`
+			Date            string `json:"date"`
+			Problem         string `json:"problem_statement"`
+			Missed          string `json:"what_i_missed"`
+			FixRule         string `json:"fix_rule"`
+			PatternRemember string `json:"pattern_to_remember"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return http.StatusBadRequest, newAPIError("bad_request", "invalid JSON body")
+		}
+
+		m := Mistake{
+			ID:              id,
+			Topic:           strings.TrimSpace(body.Topic),
+			Date:            strings.TrimSpace(body.Date),
+			Problem:         strings.TrimSpace(body.Problem),
+			Missed:          strings.TrimSpace(body.Missed),
+			FixRule:         strings.TrimSpace(body.FixRule),
+			PatternRemember: strings.TrimSpace(body.PatternRemember),
+		}
+		if m.Topic == "" || m.Date == "" || m.Problem == "" || m.Missed == "" || m.FixRule == "" || m.PatternRemember == "" {
+			return http.StatusBadRequest, newAPIError("bad_request", "all fields are required")
+		}
+
+		if _, err := a.getMistakeByID(id); errors.Is(err, sql.ErrNoRows) {
+			return http.StatusNotFound, newAPIError("not_found", "mistake not found")
+		} else if err != nil {
+			return http.StatusInternalServerError, newAPIError("internal", "failed to load mistake")
+		}
+
+		if err := a.updateMistake(m); err != nil {
+			return http.StatusInternalServerError, newAPIError("internal", "failed to update mistake")
+		}
+		writeJSON(w, http.StatusOK, m)
+		return http.StatusOK, nil
+
+	case http.MethodDelete:
+		if err := a.deleteMistake(id); err != nil {
+			return http.StatusInternalServerError, newAPIError("internal", "failed to delete mistake")
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return http.StatusNoContent, nil
+
+	default:
+		return http.StatusMethodNotAllowed, newAPIError("method_not_allowed", "method not allowed")
+	}
+}
+
+func (a *App) apiExportCSV(w http.ResponseWriter, r *http.Request) (int, error) {
+	if r.Method != http.MethodGet {
+		return http.StatusMethodNotAllowed, newAPIError("method_not_allowed", "method not allowed")
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	topic := strings.TrimSpace(r.URL.Query().Get("topic"))
+	from := strings.TrimSpace(r.URL.Query().Get("from"))
+	to := strings.TrimSpace(r.URL.Query().Get("to"))
+
+	items, err := a.searchMistakes(q, topic, from, to, 10000)
+	if err != nil {
+		return http.StatusInternalServerError, newAPIError("internal", "failed to export")
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="mistakes.csv"`)
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	_ = cw.Write([]string{"id", "date", "topic", "problem_statement", "what_i_missed", "fix_rule", "pattern_to_remember", "created_at"})
+	for _, m := range items {
+		_ = cw.Write([]string{
+			strconv.FormatInt(m.ID, 10), m.Date, m.Topic, m.Problem, m.Missed, m.FixRule, m.PatternRemember, m.CreatedAt,
+		})
+	}
+	return http.StatusOK, nil
+}
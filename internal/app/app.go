@@ -0,0 +1,41 @@
+// Package app wires together the mistake-logger server: the database, the
+// parsed templates and the prepared queries it hands to its handlers. It is
+// the internal counterpart to cmd/mistake-logger, which only does flag
+// parsing and process wiring.
+package app
+
+import (
+	"database/sql"
+	"html/template"
+	"log/slog"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/TheAkif/mistake-logger/internal/middleware/accesslog"
+)
+
+// Config holds everything needed to construct an App.
+type Config struct {
+	Addr        string
+	DBPath      string
+	TemplateDir string
+	StaticDir   string
+	AccessLog   accesslog.Config
+}
+
+// App is the shared state every handler is a method on. It replaces the
+// package-level db/tmpl globals main.go used to carry.
+type App struct {
+	db          *sql.DB
+	tmpl        *template.Template
+	queries     *Queries
+	cfg         Config
+	log         *slog.Logger
+	accessLog   func(http.Handler) http.Handler
+	requestRing *requestRing
+
+	registry            *prometheus.Registry
+	httpRequestsTotal   *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+}
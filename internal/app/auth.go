@@ -0,0 +1,249 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Session is a single logged-in session, persisted in the sessions table so
+// restarting the server doesn't force everyone to log back in.
+type Session struct {
+	ID        string
+	User      string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+const (
+	sessionCookieName = "mistake_logger_session"
+	sessionTTL        = 30 * 24 * time.Hour
+)
+
+type ctxKey int
+
+const sessionCtxKey ctxKey = 0
+
+// passwordHash returns the bcrypt hash used to gate the app, read from
+// MISTAKE_LOGGER_PASSWORD_HASH (preferred) or the plaintext
+// MISTAKE_LOGGER_PASSWORD env var hashed on the fly for convenience.
+func passwordHash() (string, error) {
+	if h := os.Getenv("MISTAKE_LOGGER_PASSWORD_HASH"); h != "" {
+		return h, nil
+	}
+	if p := os.Getenv("MISTAKE_LOGGER_PASSWORD"); p != "" {
+		h, err := bcrypt.GenerateFromPassword([]byte(p), bcrypt.DefaultCost)
+		if err != nil {
+			return "", err
+		}
+		return string(h), nil
+	}
+	return "", errors.New("no password configured: set MISTAKE_LOGGER_PASSWORD or MISTAKE_LOGGER_PASSWORD_HASH")
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (a *App) createSession(user string) (Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return Session{}, err
+	}
+	now := time.Now()
+	s := Session{
+		ID:        id,
+		User:      user,
+		CreatedAt: now,
+		ExpiresAt: now.Add(sessionTTL),
+	}
+	_, err = a.queries.CreateSession.Exec(s.ID, s.User, s.CreatedAt.Format(time.RFC3339), s.ExpiresAt.Format(time.RFC3339))
+	if err != nil {
+		return Session{}, err
+	}
+	return s, nil
+}
+
+func (a *App) deleteSession(id string) error {
+	_, err := a.queries.DeleteSession.Exec(id)
+	return err
+}
+
+func (a *App) getSession(id string) (Session, error) {
+	var s Session
+	var createdAt, expiresAt string
+	err := a.queries.GetSession.QueryRow(id).Scan(&s.ID, &s.User, &createdAt, &expiresAt)
+	if err != nil {
+		return Session{}, err
+	}
+	s.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return Session{}, err
+	}
+	s.ExpiresAt, err = time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return Session{}, err
+	}
+	if time.Now().After(s.ExpiresAt) {
+		_ = a.deleteSession(s.ID)
+		return Session{}, sql.ErrNoRows
+	}
+	return s, nil
+}
+
+// safeLocalRedirect turns a post-login redirect param into a same-site,
+// root-relative path. Browsers treat a leading backslash the same as a
+// leading slash, so "\evil.com" would otherwise parse as the path
+// "/evil.com" but render as the protocol-relative "//evil.com" once
+// prefixed with "/" — url.Parse on the backslash-normalized value and
+// rejecting anything that comes out with a Host closes that off alongside
+// the plainer "//host" and "scheme://host" forms.
+func safeLocalRedirect(redirect string) string {
+	redirect = strings.ReplaceAll(redirect, "\\", "/")
+	u, err := url.Parse(redirect)
+	if err != nil || u.Host != "" || u.Opaque != "" || !strings.HasPrefix(u.Path, "/") {
+		return "/"
+	}
+	return u.RequestURI()
+}
+
+// loginRedirectURL builds the /login?redirect=<origURI> URL for an
+// unauthenticated GET, escaping origURI so its own query string doesn't
+// clash with the outer one.
+func loginRedirectURL(origURI string) string {
+	v := url.Values{"redirect": {origURI}}
+	return "/login?" + v.Encode()
+}
+
+// sessionGet pulls the Session stashed on the request context by
+// authMiddleware. It returns ok=false for unauthenticated requests.
+func sessionGet(r *http.Request) (Session, bool) {
+	s, ok := r.Context().Value(sessionCtxKey).(Session)
+	return s, ok
+}
+
+// sessionUser returns the logged-in user for r, or "" if unauthenticated.
+// Page handlers use it to populate the User field their templates render in
+// the shared logout nav.
+func sessionUser(r *http.Request) string {
+	s, ok := sessionGet(r)
+	if !ok {
+		return ""
+	}
+	return s.User
+}
+
+// authMiddleware gates every other handler behind a logged-in session. It
+// lets /login, /logout, /metrics and /static/ through unauthenticated, redirects
+// unauthenticated GETs to /login?redirect=<orig>, and rejects unauthenticated
+// POSTs with 401.
+func (a *App) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" || r.URL.Path == "/logout" || r.URL.Path == "/metrics" || strings.HasPrefix(r.URL.Path, "/static/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cookie, err := r.Cookie(sessionCookieName)
+		if err == nil {
+			if s, err := a.getSession(cookie.Value); err == nil {
+				ctx := context.WithValue(r.Context(), sessionCtxKey, s)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+		}
+
+		if r.Method == http.MethodGet {
+			http.Redirect(w, r, loginRedirectURL(r.URL.RequestURI()), http.StatusSeeOther)
+			return
+		}
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	})
+}
+
+func (a *App) handleLogin(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if err := a.tmpl.ExecuteTemplate(w, "login.html", map[string]string{
+			"Redirect": r.URL.Query().Get("redirect"),
+		}); err != nil {
+			a.log.Error("template execute login", "error", err)
+		}
+
+	case http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Bad form", http.StatusBadRequest)
+			return
+		}
+
+		hash, err := passwordHash()
+		if err != nil {
+			a.log.Error("passwordHash", "error", err)
+			http.Error(w, "Login is not configured", http.StatusInternalServerError)
+			return
+		}
+
+		password := r.FormValue("password")
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+			http.Error(w, "Invalid password", http.StatusUnauthorized)
+			return
+		}
+
+		s, err := a.createSession("owner")
+		if err != nil {
+			a.log.Error("createSession", "error", err)
+			http.Error(w, "Failed to log in", http.StatusInternalServerError)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     sessionCookieName,
+			Value:    s.ID,
+			Path:     "/",
+			Expires:  s.ExpiresAt,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		http.Redirect(w, r, safeLocalRedirect(r.FormValue("redirect")), http.StatusSeeOther)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *App) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		if err := a.deleteSession(cookie.Value); err != nil {
+			a.log.Error("deleteSession", "error", err)
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    "",
+		Path:     "/",
+		Expires:  time.Unix(0, 0),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestLoginRedirectURL(t *testing.T) {
+	got := loginRedirectURL("/rules?topic=Foo&from=2024-01-01")
+
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatalf("loginRedirectURL produced an unparseable URL %q: %v", got, err)
+	}
+	redirect := u.Query().Get("redirect")
+	if redirect != "/rules?topic=Foo&from=2024-01-01" {
+		t.Errorf("redirect param = %q, want %q", redirect, "/rules?topic=Foo&from=2024-01-01")
+	}
+	if len(u.Query()) != 1 {
+		t.Errorf("expected only the redirect param, got %v", u.Query())
+	}
+}
+
+func TestSafeLocalRedirect(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"", "/"},
+		{"rules", "/"},
+		{"/rules?topic=Foo", "/rules?topic=Foo"},
+		{"//evil.com", "/"},
+		{"https://evil.com", "/"},
+		{`\evil.com`, "/evil.com"},
+		{`/\evil.com`, "/"},
+		{`\\evil.com`, "/"},
+	}
+	for _, c := range cases {
+		if got := safeLocalRedirect(c.in); got != c.want {
+			t.Errorf("safeLocalRedirect(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
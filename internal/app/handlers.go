@@ -0,0 +1,291 @@
+package app
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mux builds the http.Handler for the whole app: routes wrapped in the auth
+// and security-header middleware.
+func (a *App) Mux() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", a.handleIndex)
+	mux.HandleFunc("/add", a.handleAdd)
+	mux.HandleFunc("/edit", a.handleEdit)
+	mux.HandleFunc("/delete", a.handleDelete)
+	mux.HandleFunc("/export.csv", a.handleExportCSV)
+	mux.HandleFunc("/rules", a.handleRules)
+	mux.HandleFunc("/login", a.handleLogin)
+	mux.HandleFunc("/logout", a.handleLogout)
+	mux.HandleFunc("/review", a.handleReviewPage)
+	mux.HandleFunc("/admin", a.handleAdmin)
+
+	mux.HandleFunc("/api/v1/mistakes", a.adapt(a.apiMistakesCollection))
+	mux.HandleFunc("/api/v1/mistakes/", a.adapt(a.apiMistakeByID))
+	mux.HandleFunc("/api/v1/export.csv", a.adapt(a.apiExportCSV))
+	mux.HandleFunc("/api/v1/review/next", a.adapt(a.apiReviewNext))
+	mux.HandleFunc("/api/v1/review/", a.adapt(a.apiReviewGrade))
+
+	mux.Handle("/metrics", a.metricsHandler())
+	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(a.cfg.StaticDir))))
+
+	return a.accessLog(withBasicSecurityHeaders(a.metricsMiddleware(a.authMiddleware(mux))))
+}
+
+func (a *App) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	topic := strings.TrimSpace(r.URL.Query().Get("topic"))
+	from := strings.TrimSpace(r.URL.Query().Get("from"))
+	to := strings.TrimSpace(r.URL.Query().Get("to"))
+
+	if from != "" {
+		if _, err := time.Parse("2006-01-02", from); err != nil {
+			http.Error(w, "Invalid 'from' date. Use YYYY-MM-DD.", http.StatusBadRequest)
+			return
+		}
+	}
+	if to != "" {
+		if _, err := time.Parse("2006-01-02", to); err != nil {
+			http.Error(w, "Invalid 'to' date. Use YYYY-MM-DD.", http.StatusBadRequest)
+			return
+		}
+	}
+
+	mistakes, err := a.searchMistakes(q, topic, from, to, 200)
+	if err != nil {
+		http.Error(w, "Failed to load mistakes", http.StatusInternalServerError)
+		a.log.Error("searchMistakes", "error", err)
+		return
+	}
+
+	data := IndexData{
+		User:     sessionUser(r),
+		Today:    time.Now().Format("2006-01-02"),
+		QueryQ:   q,
+		Topic:    topic,
+		From:     from,
+		To:       to,
+		Mistakes: mistakes,
+		Count:    len(mistakes),
+	}
+
+	if err := a.tmpl.ExecuteTemplate(w, "index.html", data); err != nil {
+		a.log.Error("template execute index", "error", err)
+	}
+}
+
+func (a *App) handleAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad form", http.StatusBadRequest)
+		return
+	}
+
+	m, err := mistakeFromForm(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m.CreatedAt = time.Now().Format(time.RFC3339)
+	if err := a.insertMistake(m); err != nil {
+		http.Error(w, "Failed to save mistake", http.StatusInternalServerError)
+		a.log.Error("insertMistake", "error", err)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (a *App) handleEdit(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		id, ok := parseIDParam(r, "id")
+		if !ok {
+			http.Error(w, "Missing/invalid id", http.StatusBadRequest)
+			return
+		}
+
+		m, err := a.getMistakeByID(id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				http.NotFound(w, r)
+				return
+			}
+			http.Error(w, "Failed to load mistake", http.StatusInternalServerError)
+			a.log.Error("getMistakeByID", "error", err)
+			return
+		}
+
+		if err := a.tmpl.ExecuteTemplate(w, "edit.html", EditData{User: sessionUser(r), Mistake: m}); err != nil {
+			a.log.Error("template execute edit", "error", err)
+		}
+
+	case http.MethodPost:
+		id, ok := parseIDParam(r, "id")
+		if !ok {
+			http.Error(w, "Missing/invalid id", http.StatusBadRequest)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Bad form", http.StatusBadRequest)
+			return
+		}
+
+		m, err := mistakeFromForm(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m.ID = id
+
+		if err := a.updateMistake(m); err != nil {
+			http.Error(w, "Failed to update mistake", http.StatusInternalServerError)
+			a.log.Error("updateMistake", "error", err)
+			return
+		}
+
+		redirectBack(w, r)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *App) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseIDParam(r, "id")
+	if !ok {
+		http.Error(w, "Missing/invalid id", http.StatusBadRequest)
+		return
+	}
+
+	if err := a.deleteMistake(id); err != nil {
+		http.Error(w, "Failed to delete mistake", http.StatusInternalServerError)
+		a.log.Error("deleteMistake", "error", err)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (a *App) handleExportCSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	topic := strings.TrimSpace(r.URL.Query().Get("topic"))
+	from := strings.TrimSpace(r.URL.Query().Get("from"))
+	to := strings.TrimSpace(r.URL.Query().Get("to"))
+
+	if from != "" {
+		if _, err := time.Parse("2006-01-02", from); err != nil {
+			http.Error(w, "Invalid 'from' date. Use YYYY-MM-DD.", http.StatusBadRequest)
+			return
+		}
+	}
+	if to != "" {
+		if _, err := time.Parse("2006-01-02", to); err != nil {
+			http.Error(w, "Invalid 'to' date. Use YYYY-MM-DD.", http.StatusBadRequest)
+			return
+		}
+	}
+
+	items, err := a.searchMistakes(q, topic, from, to, 10000)
+	if err != nil {
+		http.Error(w, "Failed to export", http.StatusInternalServerError)
+		a.log.Error("export searchMistakes", "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="mistakes.csv"`)
+
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	_ = cw.Write([]string{"id", "date", "topic", "problem_statement", "what_i_missed", "fix_rule", "pattern_to_remember", "created_at"})
+	for _, m := range items {
+		_ = cw.Write([]string{
+			strconv.FormatInt(m.ID, 10),
+			m.Date,
+			m.Topic,
+			m.Problem,
+			m.Missed,
+			m.FixRule,
+			m.PatternRemember,
+			m.CreatedAt,
+		})
+	}
+}
+
+func (a *App) handleRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	topic := strings.TrimSpace(r.URL.Query().Get("topic"))
+	from := strings.TrimSpace(r.URL.Query().Get("from"))
+	to := strings.TrimSpace(r.URL.Query().Get("to"))
+
+	if from != "" {
+		if _, err := time.Parse("2006-01-02", from); err != nil {
+			http.Error(w, "Invalid 'from' date. Use YYYY-MM-DD.", http.StatusBadRequest)
+			return
+		}
+	}
+	if to != "" {
+		if _, err := time.Parse("2006-01-02", to); err != nil {
+			http.Error(w, "Invalid 'to' date. Use YYYY-MM-DD.", http.StatusBadRequest)
+			return
+		}
+	}
+
+	items, err := a.searchMistakes(q, topic, from, to, 500)
+	if err != nil {
+		http.Error(w, "Failed to load rules", http.StatusInternalServerError)
+		a.log.Error("rules searchMistakes", "error", err)
+		return
+	}
+
+	data := RulesData{
+		User:   sessionUser(r),
+		QueryQ: q,
+		Topic:  topic,
+		From:   from,
+		To:     to,
+		Items:  items,
+		Count:  len(items),
+	}
+
+	if err := a.tmpl.ExecuteTemplate(w, "rules.html", data); err != nil {
+		a.log.Error("template execute rules", "error", err)
+	}
+}
+
+func redirectBack(w http.ResponseWriter, r *http.Request) {
+	back := r.Referer()
+	if back == "" {
+		back = "/"
+	}
+	http.Redirect(w, r, back, http.StatusSeeOther)
+}
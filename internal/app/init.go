@@ -0,0 +1,72 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/TheAkif/mistake-logger/internal/middleware/accesslog"
+)
+
+// New opens the database, runs migrations, parses templates and prepares
+// queries, returning a fully wired App ready to serve requests.
+func New(cfg Config, log *slog.Logger) (*App, error) {
+	db, err := sql.Open("sqlite", cfg.DBPath)
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+
+	if err := initSchema(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init schema: %w", err)
+	}
+
+	tmpl, err := template.New("mistake-logger").Funcs(templateFuncs).ParseGlob(filepath.Join(cfg.TemplateDir, "*.html"))
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("parse templates: %w", err)
+	}
+
+	queries, err := prepareQueries(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("prepare queries: %w", err)
+	}
+
+	accessLogMW, err := accesslog.New(cfg.AccessLog)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init access log: %w", err)
+	}
+
+	a := &App{
+		db:          db,
+		tmpl:        tmpl,
+		queries:     queries,
+		cfg:         cfg,
+		log:         log,
+		accessLog:   accessLogMW,
+		requestRing: newRequestRing(requestRingSize),
+	}
+
+	if err := a.registerMetrics(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("register metrics: %w", err)
+	}
+
+	return a, nil
+}
+
+// requestRingSize bounds how many recent requests /admin's slowest-handlers
+// table can draw from.
+const requestRingSize = 200
+
+// Close releases the App's prepared statements and database handle.
+func (a *App) Close() error {
+	a.queries.Close()
+	return a.db.Close()
+}
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// knownRoutes lists the exact (non-prefix) paths Mux() registers. Anything
+// else is matched against the prefix routes below, or collapsed to a
+// catch-all label.
+var knownRoutes = map[string]bool{
+	"/":                   true,
+	"/add":                true,
+	"/edit":               true,
+	"/delete":             true,
+	"/export.csv":         true,
+	"/rules":              true,
+	"/login":              true,
+	"/logout":             true,
+	"/review":             true,
+	"/admin":              true,
+	"/api/v1/mistakes":    true,
+	"/api/v1/export.csv":  true,
+	"/api/v1/review/next": true,
+	"/metrics":            true,
+}
+
+// routeLabel collapses a request path to the route pattern it matches in
+// Mux(), so http_requests_total/http_request_duration_seconds stay a fixed,
+// small label series instead of growing one series per path-embedded
+// mistake ID forever.
+func routeLabel(path string) string {
+	switch {
+	case knownRoutes[path]:
+		return path
+	case strings.HasPrefix(path, "/api/v1/mistakes/"):
+		return "/api/v1/mistakes/{id}"
+	case strings.HasPrefix(path, "/api/v1/review/"):
+		return "/api/v1/review/{id}/grade"
+	case strings.HasPrefix(path, "/static/"):
+		return "/static/*"
+	default:
+		return "other"
+	}
+}
+
+// statusWriter wraps http.ResponseWriter to capture the status code that was
+// actually written, which net/http doesn't expose to middleware otherwise.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+// requestTiming is one entry in the App's in-memory ring buffer of recent
+// requests, surfaced on /admin as the slowest-handlers table.
+type requestTiming struct {
+	Path     string
+	Method   string
+	Status   int
+	Duration time.Duration
+	At       time.Time
+}
+
+// requestRing is a fixed-size, overwrite-oldest ring buffer of request
+// timings, safe for concurrent use by the metrics middleware.
+type requestRing struct {
+	mu   sync.Mutex
+	buf  []requestTiming
+	size int
+	next int
+}
+
+func newRequestRing(size int) *requestRing {
+	return &requestRing{buf: make([]requestTiming, 0, size), size: size}
+}
+
+func (r *requestRing) add(t requestTiming) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.buf) < r.size {
+		r.buf = append(r.buf, t)
+		return
+	}
+	r.buf[r.next] = t
+	r.next = (r.next + 1) % r.size
+}
+
+// slowest returns up to n entries from the buffer, sorted slowest-first.
+func (r *requestRing) slowest(n int) []requestTiming {
+	r.mu.Lock()
+	out := append([]requestTiming(nil), r.buf...)
+	r.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Duration > out[j].Duration })
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// metricsMiddleware records http_requests_total and
+// http_request_duration_seconds for every request and appends it to the
+// App's request ring buffer.
+func (a *App) metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(sw, r)
+
+		dur := time.Since(start)
+		label := routeLabel(r.URL.Path)
+		a.httpRequestsTotal.WithLabelValues(label, r.Method, strconv.Itoa(sw.status)).Inc()
+		a.httpRequestDuration.WithLabelValues(label, r.Method).Observe(dur.Seconds())
+		a.requestRing.add(requestTiming{
+			Path:     r.URL.Path,
+			Method:   r.Method,
+			Status:   sw.status,
+			Duration: dur,
+			At:       start,
+		})
+	})
+}
+
+// mistakeStatsCollector is scraped on demand (rather than kept in a gauge
+// updated on a timer) so /metrics always reflects the current row counts.
+type mistakeStatsCollector struct {
+	app       *App
+	totalDesc *prometheus.Desc
+	topicDesc *prometheus.Desc
+}
+
+func newMistakeStatsCollector(a *App) *mistakeStatsCollector {
+	return &mistakeStatsCollector{
+		app:       a,
+		totalDesc: prometheus.NewDesc("mistakes_total", "Total number of logged mistakes.", nil, nil),
+		topicDesc: prometheus.NewDesc("mistakes_by_topic", "Number of logged mistakes per topic.", []string{"topic"}, nil),
+	}
+}
+
+func (c *mistakeStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.totalDesc
+	ch <- c.topicDesc
+}
+
+func (c *mistakeStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	total, byTopic, err := c.app.mistakeCounts()
+	if err != nil {
+		c.app.log.Error("mistake stats collector", "error", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.totalDesc, prometheus.GaugeValue, float64(total))
+	for topic, n := range byTopic {
+		ch <- prometheus.MustNewConstMetric(c.topicDesc, prometheus.GaugeValue, float64(n), topic)
+	}
+}
+
+// registerMetrics creates a.registry, a dedicated Prometheus registry owned
+// by this App rather than the global prometheus.DefaultRegisterer, and wires
+// up every collector onto it: the request counters/histogram, the mistake
+// row-count collector, and the db_open_connections gauge backed by
+// db.Stats(). A dedicated registry means a second app.New in the same
+// process (tests, hot-reload) gets its own metric series instead of
+// panicking on duplicate registration against the global default.
+func (a *App) registerMetrics() error {
+	a.registry = prometheus.NewRegistry()
+	factory := promauto.With(a.registry)
+
+	a.httpRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total HTTP requests, labeled by route, method and status.",
+	}, []string{"path", "method", "status"})
+
+	a.httpRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "http_request_duration_seconds",
+		Help: "HTTP request latency in seconds, labeled by route and method.",
+	}, []string{"path", "method"})
+
+	if err := a.registry.Register(newMistakeStatsCollector(a)); err != nil {
+		return err
+	}
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "db_open_connections",
+		Help: "Number of open connections to the sqlite database.",
+	}, func() float64 { return float64(a.db.Stats().OpenConnections) })
+	return nil
+}
+
+func (a *App) metricsHandler() http.Handler {
+	return promhttp.HandlerFor(a.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,23 @@
+package app
+
+import "testing"
+
+func TestRouteLabel(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"/", "/"},
+		{"/api/v1/mistakes", "/api/v1/mistakes"},
+		{"/api/v1/mistakes/123", "/api/v1/mistakes/{id}"},
+		{"/api/v1/review/next", "/api/v1/review/next"},
+		{"/api/v1/review/123/grade", "/api/v1/review/{id}/grade"},
+		{"/static/style.css", "/static/*"},
+		{"/nonexistent", "other"},
+	}
+	for _, c := range cases {
+		if got := routeLabel(c.in); got != c.want {
+			t.Errorf("routeLabel(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Mistake struct {
+	ID              int64  `json:"id"`
+	Topic           string `json:"topic"`
+	Date            string `json:"date"`
+	Problem         string `json:"problem_statement"`
+	Missed          string `json:"what_i_missed"`
+	FixRule         string `json:"fix_rule"`
+	PatternRemember string `json:"pattern_to_remember"`
+	CreatedAt       string `json:"created_at"`
+	// Snippet is only populated by the FTS5 search path in searchMistakes; it
+	// holds a <mark>-highlighted excerpt of whichever column matched.
+	Snippet string `json:"snippet,omitempty"`
+
+	// SM-2 spaced-repetition state, updated by the /api/v1/review/{id}/grade
+	// endpoint. Ease starts at 2.5 per the standard SM-2 default.
+	Ease         float64 `json:"ease"`
+	IntervalDays int     `json:"interval_days"`
+	Reps         int     `json:"reps"`
+	DueDate      string  `json:"due_date"`
+}
+
+type IndexData struct {
+	User     string
+	Today    string
+	QueryQ   string
+	Topic    string
+	From     string
+	To       string
+	Mistakes []Mistake
+	Count    int
+}
+
+type EditData struct {
+	User    string
+	Mistake Mistake
+}
+
+type RulesData struct {
+	User   string
+	QueryQ string
+	Topic  string
+	From   string
+	To     string
+	Items  []Mistake
+	Count  int
+}
+
+func (a *App) insertMistake(m Mistake) error {
+	_, err := a.queries.InsertMistake.Exec(m.Topic, m.Date, m.Problem, m.Missed, m.FixRule, m.PatternRemember, m.CreatedAt)
+	return err
+}
+
+func (a *App) getMistakeByID(id int64) (Mistake, error) {
+	var m Mistake
+	err := a.queries.GetMistakeByID.QueryRow(id).Scan(
+		&m.ID, &m.Topic, &m.Date, &m.Problem, &m.Missed, &m.FixRule, &m.PatternRemember, &m.CreatedAt,
+		&m.Ease, &m.IntervalDays, &m.Reps, &nullString{&m.DueDate})
+	return m, err
+}
+
+func (a *App) updateMistake(m Mistake) error {
+	_, err := a.queries.UpdateMistake.Exec(m.Topic, m.Date, m.Problem, m.Missed, m.FixRule, m.PatternRemember, m.ID)
+	return err
+}
+
+func (a *App) deleteMistake(id int64) error {
+	_, err := a.queries.DeleteMistake.Exec(id)
+	return err
+}
+
+func mistakeFromForm(r *http.Request) (Mistake, error) {
+	topic := strings.TrimSpace(r.FormValue("topic"))
+	date := strings.TrimSpace(r.FormValue("date"))
+	problem := strings.TrimSpace(r.FormValue("problem_statement"))
+	missed := strings.TrimSpace(r.FormValue("what_i_missed"))
+	fix := strings.TrimSpace(r.FormValue("fix_rule"))
+	pattern := strings.TrimSpace(r.FormValue("pattern_to_remember"))
+
+	if topic == "" || date == "" || problem == "" || missed == "" || fix == "" || pattern == "" {
+		return Mistake{}, errText("All fields are required.")
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return Mistake{}, errText("Invalid date format. Use YYYY-MM-DD.")
+	}
+
+	return Mistake{
+		Topic:           topic,
+		Date:            date,
+		Problem:         problem,
+		Missed:          missed,
+		FixRule:         fix,
+		PatternRemember: pattern,
+	}, nil
+}
+
+type errText string
+
+func (e errText) Error() string { return string(e) }
+
+// nullString scans a nullable TEXT column into a plain string, leaving it
+// empty for NULL instead of requiring callers to juggle sql.NullString.
+type nullString struct{ dst *string }
+
+func (n *nullString) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*n.dst = ""
+	case string:
+		*n.dst = v
+	case []byte:
+		*n.dst = string(v)
+	default:
+		return errText("nullString: unsupported type")
+	}
+	return nil
+}
+
+func parseIDParam(r *http.Request, key string) (int64, bool) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		raw = r.FormValue(key)
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,97 @@
+package app
+
+import "database/sql"
+
+// Queries holds every prepared statement the handlers use, so a call like
+// insertMistake becomes app.queries.InsertMistake.Exec(...) instead of a
+// fresh db.Exec with an inline query string per call site.
+type Queries struct {
+	InsertMistake  *sql.Stmt
+	GetMistakeByID *sql.Stmt
+	UpdateMistake  *sql.Stmt
+	DeleteMistake  *sql.Stmt
+
+	CreateSession *sql.Stmt
+	GetSession    *sql.Stmt
+	DeleteSession *sql.Stmt
+
+	GetDueMistake *sql.Stmt
+	GradeMistake  *sql.Stmt
+}
+
+func prepareQueries(db *sql.DB) (*Queries, error) {
+	q := &Queries{}
+	var err error
+
+	stmts := []struct {
+		dst   **sql.Stmt
+		query string
+	}{
+		{&q.InsertMistake, `
+INSERT INTO mistakes (topic, date, problem_statement, what_i_missed, fix_rule, pattern_to_remember, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+`},
+		{&q.GetMistakeByID, `
+SELECT id, topic, date, problem_statement, what_i_missed, fix_rule, pattern_to_remember, created_at,
+       ease, interval_days, reps, due_date
+FROM mistakes
+WHERE id = ?;
+`},
+		{&q.UpdateMistake, `
+UPDATE mistakes
+SET topic = ?, date = ?, problem_statement = ?, what_i_missed = ?, fix_rule = ?, pattern_to_remember = ?
+WHERE id = ?;
+`},
+		{&q.DeleteMistake, `DELETE FROM mistakes WHERE id = ?;`},
+
+		{&q.CreateSession, `
+INSERT INTO sessions (id, user, created_at, expires_at)
+VALUES (?, ?, ?, ?);
+`},
+		{&q.GetSession, `
+SELECT id, user, created_at, expires_at
+FROM sessions
+WHERE id = ?;
+`},
+		{&q.DeleteSession, `DELETE FROM sessions WHERE id = ?;`},
+
+		{&q.GetDueMistake, `
+SELECT id, topic, date, problem_statement, what_i_missed, fix_rule, pattern_to_remember, created_at,
+       ease, interval_days, reps, due_date
+FROM mistakes
+WHERE due_date IS NULL OR due_date <= ?
+ORDER BY due_date IS NOT NULL, due_date ASC, id ASC
+LIMIT 1;
+`},
+		{&q.GradeMistake, `
+UPDATE mistakes
+SET ease = ?, interval_days = ?, reps = ?, due_date = ?
+WHERE id = ?;
+`},
+	}
+
+	for _, s := range stmts {
+		*s.dst, err = db.Prepare(s.query)
+		if err != nil {
+			q.Close()
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+// Close releases all prepared statements. Errors are not accumulated since
+// this only runs during shutdown or after a failed prepareQueries.
+func (q *Queries) Close() {
+	stmts := []*sql.Stmt{
+		q.InsertMistake, q.GetMistakeByID, q.UpdateMistake, q.DeleteMistake,
+		q.CreateSession, q.GetSession, q.DeleteSession,
+		q.GetDueMistake, q.GradeMistake,
+	}
+	for _, s := range stmts {
+		if s != nil {
+			s.Close()
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package app
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gradeSM2 applies the SM-2 spaced-repetition update to m for a 0-5 quality
+// score and returns the new ease/interval/reps/due_date. today is the date
+// the grade was recorded; due_date is formatted as YYYY-MM-DD.
+func gradeSM2(m Mistake, quality int, today time.Time) Mistake {
+	if quality < 3 {
+		m.Reps = 0
+		m.IntervalDays = 1
+	} else {
+		m.Reps++
+		switch m.Reps {
+		case 1:
+			m.IntervalDays = 1
+		case 2:
+			m.IntervalDays = 6
+		default:
+			m.IntervalDays = int(math.Round(float64(m.IntervalDays) * m.Ease))
+		}
+		m.Ease = m.Ease + 0.1 - float64(5-quality)*(0.08+float64(5-quality)*0.02)
+		if m.Ease < 1.3 {
+			m.Ease = 1.3
+		}
+	}
+	m.DueDate = today.AddDate(0, 0, m.IntervalDays).Format("2006-01-02")
+	return m
+}
+
+func (a *App) nextDueMistake() (Mistake, error) {
+	today := time.Now().Format("2006-01-02")
+	var m Mistake
+	err := a.queries.GetDueMistake.QueryRow(today).Scan(
+		&m.ID, &m.Topic, &m.Date, &m.Problem, &m.Missed, &m.FixRule, &m.PatternRemember, &m.CreatedAt,
+		&m.Ease, &m.IntervalDays, &m.Reps, &nullString{&m.DueDate})
+	return m, err
+}
+
+func (a *App) apiReviewNext(w http.ResponseWriter, r *http.Request) (int, error) {
+	if r.Method != http.MethodGet {
+		return http.StatusMethodNotAllowed, newAPIError("method_not_allowed", "method not allowed")
+	}
+
+	m, err := a.nextDueMistake()
+	if err == sql.ErrNoRows {
+		return http.StatusNotFound, newAPIError("not_found", "nothing due for review")
+	}
+	if err != nil {
+		return http.StatusInternalServerError, newAPIError("internal", "failed to load next review")
+	}
+	writeJSON(w, http.StatusOK, m)
+	return http.StatusOK, nil
+}
+
+// apiReviewGrade handles POST /api/v1/review/{id}/grade.
+func (a *App) apiReviewGrade(w http.ResponseWriter, r *http.Request) (int, error) {
+	if r.Method != http.MethodPost {
+		return http.StatusMethodNotAllowed, newAPIError("method_not_allowed", "method not allowed")
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/review/")
+	idStr, ok := strings.CutSuffix(rest, "/grade")
+	if !ok {
+		return http.StatusNotFound, newAPIError("not_found", "unknown route")
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		return http.StatusBadRequest, newAPIError("bad_request", "invalid mistake id")
+	}
+
+	var body struct {
+		Quality int `json:"quality"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return http.StatusBadRequest, newAPIError("bad_request", "invalid JSON body")
+	}
+	if body.Quality < 0 || body.Quality > 5 {
+		return http.StatusBadRequest, newAPIError("bad_request", "quality must be between 0 and 5")
+	}
+
+	m, err := a.getMistakeByID(id)
+	if err == sql.ErrNoRows {
+		return http.StatusNotFound, newAPIError("not_found", "mistake not found")
+	}
+	if err != nil {
+		return http.StatusInternalServerError, newAPIError("internal", "failed to load mistake")
+	}
+
+	m = gradeSM2(m, body.Quality, time.Now())
+	if _, err := a.queries.GradeMistake.Exec(m.Ease, m.IntervalDays, m.Reps, m.DueDate, m.ID); err != nil {
+		return http.StatusInternalServerError, newAPIError("internal", "failed to save grade")
+	}
+
+	writeJSON(w, http.StatusOK, m)
+	return http.StatusOK, nil
+}
+
+// handleReviewPage renders the /review HTML page: whatever mistake is
+// currently due, with a form to grade it 0-5.
+func (a *App) handleReviewPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := sessionUser(r)
+
+	m, err := a.nextDueMistake()
+	if err == sql.ErrNoRows {
+		if err := a.tmpl.ExecuteTemplate(w, "review.html", map[string]any{"User": user, "Mistake": nil}); err != nil {
+			a.log.Error("template execute review", "error", err)
+		}
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load next review", http.StatusInternalServerError)
+		a.log.Error("nextDueMistake", "error", err)
+		return
+	}
+
+	if err := a.tmpl.ExecuteTemplate(w, "review.html", map[string]any{"User": user, "Mistake": m}); err != nil {
+		a.log.Error("template execute review", "error", err)
+	}
+}
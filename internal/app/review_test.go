@@ -0,0 +1,51 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGradeSM2(t *testing.T) {
+	today := time.Date(2026, 7, 26, 0, 0, 0, 0, time.UTC)
+
+	t.Run("first correct recall", func(t *testing.T) {
+		m := gradeSM2(Mistake{Ease: 2.5, Reps: 0, IntervalDays: 0}, 4, today)
+		if m.Reps != 1 || m.IntervalDays != 1 {
+			t.Errorf("got reps=%d interval=%d, want reps=1 interval=1", m.Reps, m.IntervalDays)
+		}
+		if m.DueDate != "2026-07-27" {
+			t.Errorf("due date = %q, want %q", m.DueDate, "2026-07-27")
+		}
+	})
+
+	t.Run("second correct recall", func(t *testing.T) {
+		m := gradeSM2(Mistake{Ease: 2.5, Reps: 1, IntervalDays: 1}, 5, today)
+		if m.Reps != 2 || m.IntervalDays != 6 {
+			t.Errorf("got reps=%d interval=%d, want reps=2 interval=6", m.Reps, m.IntervalDays)
+		}
+	})
+
+	t.Run("third correct recall multiplies by ease", func(t *testing.T) {
+		m := gradeSM2(Mistake{Ease: 2.5, Reps: 2, IntervalDays: 6}, 4, today)
+		if m.Reps != 3 || m.IntervalDays != 15 {
+			t.Errorf("got reps=%d interval=%d, want reps=3 interval=15", m.Reps, m.IntervalDays)
+		}
+	})
+
+	t.Run("quality below 3 resets reps", func(t *testing.T) {
+		m := gradeSM2(Mistake{Ease: 2.5, Reps: 4, IntervalDays: 20}, 2, today)
+		if m.Reps != 0 || m.IntervalDays != 1 {
+			t.Errorf("got reps=%d interval=%d, want reps=0 interval=1", m.Reps, m.IntervalDays)
+		}
+		if m.Ease != 2.5 {
+			t.Errorf("ease = %v, want unchanged 2.5", m.Ease)
+		}
+	})
+
+	t.Run("ease floor", func(t *testing.T) {
+		m := gradeSM2(Mistake{Ease: 1.3, Reps: 3, IntervalDays: 10}, 3, today)
+		if m.Ease < 1.3 {
+			t.Errorf("ease = %v, want >= 1.3", m.Ease)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package app
+
+import "database/sql"
+
+func initSchema(db *sql.DB) error {
+	schema := `
+CREATE TABLE IF NOT EXISTS mistakes (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  topic TEXT NOT NULL,
+  date TEXT NOT NULL, -- YYYY-MM-DD
+  problem_statement TEXT NOT NULL,
+  what_i_missed TEXT NOT NULL,
+  fix_rule TEXT NOT NULL,
+  pattern_to_remember TEXT NOT NULL,
+  created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_mistakes_date ON mistakes(date DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_mistakes_topic ON mistakes(topic);
+
+CREATE TABLE IF NOT EXISTS sessions (
+  id TEXT PRIMARY KEY,
+  user TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  expires_at TEXT NOT NULL
+);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS mistakes_fts USING fts5(
+  topic,
+  problem_statement,
+  what_i_missed,
+  fix_rule,
+  pattern_to_remember,
+  content='mistakes',
+  content_rowid='id'
+);
+
+CREATE TRIGGER IF NOT EXISTS mistakes_ai AFTER INSERT ON mistakes BEGIN
+  INSERT INTO mistakes_fts(rowid, topic, problem_statement, what_i_missed, fix_rule, pattern_to_remember)
+  VALUES (new.id, new.topic, new.problem_statement, new.what_i_missed, new.fix_rule, new.pattern_to_remember);
+END;
+
+CREATE TRIGGER IF NOT EXISTS mistakes_ad AFTER DELETE ON mistakes BEGIN
+  INSERT INTO mistakes_fts(mistakes_fts, rowid, topic, problem_statement, what_i_missed, fix_rule, pattern_to_remember)
+  VALUES ('delete', old.id, old.topic, old.problem_statement, old.what_i_missed, old.fix_rule, old.pattern_to_remember);
+END;
+
+CREATE TRIGGER IF NOT EXISTS mistakes_au AFTER UPDATE ON mistakes BEGIN
+  INSERT INTO mistakes_fts(mistakes_fts, rowid, topic, problem_statement, what_i_missed, fix_rule, pattern_to_remember)
+  VALUES ('delete', old.id, old.topic, old.problem_statement, old.what_i_missed, old.fix_rule, old.pattern_to_remember);
+  INSERT INTO mistakes_fts(rowid, topic, problem_statement, what_i_missed, fix_rule, pattern_to_remember)
+  VALUES (new.id, new.topic, new.problem_statement, new.what_i_missed, new.fix_rule, new.pattern_to_remember);
+END;
+`
+	if _, err := db.Exec(schema); err != nil {
+		return err
+	}
+	if err := migrateFTS(db); err != nil {
+		return err
+	}
+	return migrateSM2Columns(db)
+}
+
+// migrateSM2Columns adds the spaced-repetition columns to mistakes for
+// databases created before they existed. SQLite has no "ADD COLUMN IF NOT
+// EXISTS", so the existing columns are inspected via PRAGMA table_info first.
+func migrateSM2Columns(db *sql.DB) error {
+	existing, err := tableColumns(db, "mistakes")
+	if err != nil {
+		return err
+	}
+
+	columns := []struct {
+		name string
+		ddl  string
+	}{
+		{"ease", "ALTER TABLE mistakes ADD COLUMN ease REAL DEFAULT 2.5"},
+		{"interval_days", "ALTER TABLE mistakes ADD COLUMN interval_days INTEGER DEFAULT 0"},
+		{"reps", "ALTER TABLE mistakes ADD COLUMN reps INTEGER DEFAULT 0"},
+		{"due_date", "ALTER TABLE mistakes ADD COLUMN due_date TEXT"},
+	}
+	for _, c := range columns {
+		if existing[c.name] {
+			continue
+		}
+		if _, err := db.Exec(c.ddl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func tableColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?);`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		cols[name] = true
+	}
+	return cols, rows.Err()
+}
+
+// migrateFTS backfills mistakes_fts for databases that already had a
+// mistakes table before the virtual table existed.
+func migrateFTS(db *sql.DB) error {
+	var count int
+	// count(*) on mistakes_fts itself would read the external content table
+	// (mistakes), so the docsize shadow table is what tells an empty index.
+	if err := db.QueryRow(`SELECT count(*) FROM mistakes_fts_docsize;`).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err := db.Exec(`
+INSERT INTO mistakes_fts(rowid, topic, problem_statement, what_i_missed, fix_rule, pattern_to_remember)
+SELECT id, topic, problem_statement, what_i_missed, fix_rule, pattern_to_remember FROM mistakes;
+`)
+	return err
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"strings"
+)
+
+func (a *App) searchMistakes(q, topic, from, to string, limit int) ([]Mistake, error) {
+	if q != "" {
+		out, err := a.searchMistakesFTS(q, topic, from, to, limit)
+		if err == nil {
+			return out, nil
+		}
+		a.log.Warn("searchMistakesFTS, falling back to LIKE", "error", err)
+	}
+	return a.searchMistakesLike(q, topic, from, to, limit)
+}
+
+// searchMistakesFTS runs the query through the mistakes_fts virtual table,
+// ranked by bm25. It returns an error (instead of falling back itself) if the
+// tokenized query fails to parse as an FTS5 MATCH expression, so the caller
+// can fall back to the LIKE path. A query that tokenizes to nothing goes
+// straight to searchMistakesLike.
+func (a *App) searchMistakesFTS(q, topic, from, to string, limit int) ([]Mistake, error) {
+	match := ftsMatchQuery(q)
+	if match == "" {
+		return a.searchMistakesLike(q, topic, from, to, limit)
+	}
+
+	var where []string
+	args := []any{match}
+
+	if topic != "" {
+		where = append(where, "mistakes.topic = ?")
+		args = append(args, topic)
+	}
+	if from != "" {
+		where = append(where, "mistakes.date >= ?")
+		args = append(args, from)
+	}
+	if to != "" {
+		where = append(where, "mistakes.date <= ?")
+		args = append(args, to)
+	}
+
+	query := `
+SELECT mistakes.id, mistakes.topic, mistakes.date, mistakes.problem_statement, mistakes.what_i_missed,
+       mistakes.fix_rule, mistakes.pattern_to_remember, mistakes.created_at,
+       snippet(mistakes_fts, -1, '<mark>', '</mark>', '…', 15)
+FROM mistakes
+JOIN mistakes_fts ON mistakes.id = mistakes_fts.rowid
+WHERE mistakes_fts MATCH ?
+`
+	if len(where) > 0 {
+		query += "AND " + strings.Join(where, " AND ") + "\n"
+	}
+	query += "ORDER BY bm25(mistakes_fts), date DESC\nLIMIT ?;"
+	args = append(args, limit)
+
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Mistake
+	for rows.Next() {
+		var m Mistake
+		if err := rows.Scan(&m.ID, &m.Topic, &m.Date, &m.Problem, &m.Missed, &m.FixRule, &m.PatternRemember, &m.CreatedAt, &m.Snippet); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// ftsMatchQuery tokenizes a user search string into an FTS5 MATCH
+// expression, wrapping bare terms with a trailing `*` for prefix matching.
+func ftsMatchQuery(q string) string {
+	fields := strings.Fields(q)
+	terms := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.Map(func(r rune) rune {
+			if r == '"' || r == '*' {
+				return -1
+			}
+			return r
+		}, f)
+		if f == "" {
+			continue
+		}
+		terms = append(terms, `"`+f+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+func (a *App) searchMistakesLike(q, topic, from, to string, limit int) ([]Mistake, error) {
+	var where []string
+	var args []any
+
+	if topic != "" {
+		where = append(where, "topic = ?")
+		args = append(args, topic)
+	}
+	if from != "" {
+		where = append(where, "date >= ?")
+		args = append(args, from)
+	}
+	if to != "" {
+		where = append(where, "date <= ?")
+		args = append(args, to)
+	}
+	if q != "" {
+		like := "%" + q + "%"
+		where = append(where, `(topic LIKE ? OR problem_statement LIKE ? OR what_i_missed LIKE ? OR fix_rule LIKE ? OR pattern_to_remember LIKE ?)`)
+		args = append(args, like, like, like, like, like)
+	}
+
+	query := `
+SELECT id, topic, date, problem_statement, what_i_missed, fix_rule, pattern_to_remember, created_at
+FROM mistakes
+`
+	if len(where) > 0 {
+		query += "WHERE " + strings.Join(where, " AND ") + "\n"
+	}
+	query += "ORDER BY date DESC, id DESC\nLIMIT ?;"
+	args = append(args, limit)
+
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Mistake
+	for rows.Next() {
+		var m Mistake
+		if err := rows.Scan(&m.ID, &m.Topic, &m.Date, &m.Problem, &m.Missed, &m.FixRule, &m.PatternRemember, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		if q != "" {
+			m.Snippet = plainSnippet(m, q)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// plainSnippet is the LIKE-path equivalent of FTS5's snippet(): it finds the
+// first field containing q (case-insensitively), and returns a short
+// <mark>-highlighted excerpt around the match, so index.html can render a
+// Snippet regardless of which search path served the result.
+func plainSnippet(m Mistake, q string) string {
+	const radius = 40
+
+	fields := []string{m.Problem, m.Missed, m.FixRule, m.PatternRemember, m.Topic}
+	lowerQ := strings.ToLower(q)
+
+	for _, field := range fields {
+		idx := strings.Index(strings.ToLower(field), lowerQ)
+		if idx < 0 {
+			continue
+		}
+
+		start := idx - radius
+		prefix := "…"
+		if start <= 0 {
+			start = 0
+			prefix = ""
+		}
+		end := idx + len(q) + radius
+		suffix := "…"
+		if end >= len(field) {
+			end = len(field)
+			suffix = ""
+		}
+
+		return prefix + field[start:idx] + "<mark>" + field[idx:idx+len(q)] + "</mark>" + field[idx+len(q):end] + suffix
+	}
+	return ""
+}
@@ -0,0 +1,33 @@
+package app
+
+import "testing"
+
+func TestFtsMatchQuery(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"nil pointer", `"nil"* "pointer"*`},
+		{`say "hi"*`, `"say"* "hi"*`},
+	}
+	for _, c := range cases {
+		if got := ftsMatchQuery(c.in); got != c.want {
+			t.Errorf("ftsMatchQuery(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestPlainSnippet(t *testing.T) {
+	m := Mistake{Problem: "Forgot to check the nil pointer before dereferencing it in the handler."}
+
+	got := plainSnippet(m, "nil pointer")
+	want := "Forgot to check the <mark>nil pointer</mark> before dereferencing it in the handler."
+	if got != want {
+		t.Errorf("plainSnippet = %q, want %q", got, want)
+	}
+
+	if got := plainSnippet(m, "does not appear"); got != "" {
+		t.Errorf("plainSnippet with no match = %q, want empty", got)
+	}
+}
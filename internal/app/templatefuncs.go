@@ -0,0 +1,33 @@
+package app
+
+import (
+	"html/template"
+	"strings"
+)
+
+var templateFuncs = template.FuncMap{
+	"snippetHTML": snippetHTML,
+}
+
+// snippetHTML renders a Mistake.Snippet value, which contains literal
+// <mark>/</mark> markers around the matched text but is otherwise built from
+// untrusted mistake content. It HTML-escapes everything except those
+// markers, so index.html can highlight matches without opening a stored-XSS
+// hole through the topic/problem/etc. fields.
+func snippetHTML(snippet string) template.HTML {
+	var b strings.Builder
+	for i, part := range strings.Split(snippet, "<mark>") {
+		if i == 0 {
+			b.WriteString(template.HTMLEscapeString(part))
+			continue
+		}
+		inMark, rest, found := strings.Cut(part, "</mark>")
+		b.WriteString("<mark>")
+		b.WriteString(template.HTMLEscapeString(inMark))
+		b.WriteString("</mark>")
+		if found {
+			b.WriteString(template.HTMLEscapeString(rest))
+		}
+	}
+	return template.HTML(b.String())
+}
@@ -0,0 +1,11 @@
+package app
+
+import "testing"
+
+func TestSnippetHTMLEscapesUntrustedContent(t *testing.T) {
+	got := snippetHTML(`before <script>alert(1)</script> <mark>nil</mark> pointer`)
+	want := `before &lt;script&gt;alert(1)&lt;/script&gt; <mark>nil</mark> pointer`
+	if string(got) != want {
+		t.Errorf("snippetHTML = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+// Package accesslog wraps an http.Handler to emit one line per request in a
+// configurable format modeled on Apache's mod_log_config, e.g. the combined
+// format: `%h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-Agent}i" %D`.
+package accesslog
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Config controls the log line format and where it's written.
+type Config struct {
+	// Format is an Apache mod_log_config-style directive string. Defaults to
+	// the combined log format if empty.
+	Format string `json:"format"`
+	// Destination is "stdout" (default), "syslog", or a file path. File
+	// destinations are rotated via lumberjack.
+	Destination string `json:"destination"`
+	MaxSizeMB   int    `json:"max_size_mb"`
+	MaxBackups  int    `json:"max_backups"`
+	MaxAgeDays  int    `json:"max_age_days"`
+	// TrustedProxies lists the remote IPs allowed to set X-Forwarded-For for
+	// %h. Requests from anywhere else keep their actual RemoteAddr as %h;
+	// use %{X-Forwarded-For}i to log the raw header regardless.
+	TrustedProxies []string `json:"trusted_proxies"`
+}
+
+const CombinedFormat = `%h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-Agent}i" %D`
+
+// New parses cfg.Format into a text/template and opens cfg.Destination,
+// returning middleware that logs one rendered line per request.
+func New(cfg Config) (func(http.Handler) http.Handler, error) {
+	format := cfg.Format
+	if format == "" {
+		format = CombinedFormat
+	}
+
+	tmpl, err := template.New("accesslog").Parse(rewriteDirectives(format))
+	if err != nil {
+		return nil, fmt.Errorf("parse access log format: %w", err)
+	}
+
+	dest, err := openDestination(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("open access log destination: %w", err)
+	}
+
+	trustedProxies := make(map[string]bool, len(cfg.TrustedProxies))
+	for _, ip := range cfg.TrustedProxies {
+		trustedProxies[ip] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(sw, r)
+
+			entry := logEntryFor(r, sw, time.Since(start), trustedProxies)
+			if err := tmpl.Execute(dest, entry); err != nil {
+				fmt.Fprintln(os.Stderr, "accesslog: render entry:", err)
+				return
+			}
+			fmt.Fprintln(dest)
+		})
+	}, nil
+}
+
+// directivePattern matches the Apache LogFormat directives this package
+// understands: %h %l %u %t %r %s %>s %b %D and %{HeaderName}i.
+var directivePattern = regexp.MustCompile(`%(\{[^}]+\}i|>?[a-zA-Z])`)
+
+// rewriteDirectives turns an Apache LogFormat string into a text/template
+// body by replacing each directive token with the field lookup it maps to.
+func rewriteDirectives(format string) string {
+	return directivePattern.ReplaceAllStringFunc(format, func(tok string) string {
+		directive := strings.TrimPrefix(tok, "%")
+		switch {
+		case strings.HasPrefix(directive, "{") && strings.HasSuffix(directive, "}i"):
+			header := directive[1 : len(directive)-2]
+			return fmt.Sprintf(`{{index .Headers %q}}`, header)
+		case directive == "h":
+			return "{{.Host}}"
+		case directive == "l":
+			return "{{.RemoteLogname}}"
+		case directive == "u":
+			return "{{.RemoteUser}}"
+		case directive == "t":
+			return "{{.Time}}"
+		case directive == "r":
+			return "{{.Request}}"
+		case directive == "s", directive == ">s":
+			return "{{.Status}}"
+		case directive == "b":
+			return "{{.Bytes}}"
+		case directive == "D":
+			return "{{.Duration}}"
+		default:
+			return tok
+		}
+	})
+}
+
+func openDestination(cfg Config) (io.Writer, error) {
+	switch cfg.Destination {
+	case "", "stdout":
+		return os.Stdout, nil
+	case "syslog":
+		return syslog.New(syslog.LOG_INFO|syslog.LOG_LOCAL0, "mistake-logger")
+	default:
+		return &lumberjack.Logger{
+			Filename:   cfg.Destination,
+			MaxSize:    orDefault(cfg.MaxSizeMB, 100),
+			MaxBackups: orDefault(cfg.MaxBackups, 5),
+			MaxAge:     orDefault(cfg.MaxAgeDays, 28),
+		}, nil
+	}
+}
+
+func orDefault(v, def int) int {
+	if v == 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,30 @@
+package accesslog
+
+import "testing"
+
+func TestRewriteDirectives(t *testing.T) {
+	got := rewriteDirectives(CombinedFormat)
+	want := `{{.Host}} {{.RemoteLogname}} {{.RemoteUser}} {{.Time}} "{{.Request}}" {{.Status}} {{.Bytes}} "{{index .Headers "Referer"}}" "{{index .Headers "User-Agent"}}" {{.Duration}}`
+	if got != want {
+		t.Errorf("rewriteDirectives(CombinedFormat) = %q, want %q", got, want)
+	}
+}
+
+func TestFirstForwardedIP(t *testing.T) {
+	cases := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"", "", false},
+		{"203.0.113.5", "203.0.113.5", true},
+		{"203.0.113.5, 10.0.0.1", "203.0.113.5", true},
+		{`FORGED" evil-injected-field "500 99999`, "", false},
+	}
+	for _, c := range cases {
+		got, ok := firstForwardedIP(c.in)
+		if got != c.want || ok != c.wantOK {
+			t.Errorf("firstForwardedIP(%q) = (%q, %v), want (%q, %v)", c.in, got, ok, c.want, c.wantOK)
+		}
+	}
+}
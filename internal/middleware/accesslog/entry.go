@@ -0,0 +1,100 @@
+package accesslog
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statusWriter wraps http.ResponseWriter to capture the status code and
+// byte count that were actually written, which net/http doesn't expose.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	n, err := sw.ResponseWriter.Write(b)
+	sw.bytes += int64(n)
+	return n, err
+}
+
+// logEntryData is what the parsed format template renders against.
+type logEntryData struct {
+	Host          string
+	RemoteLogname string
+	RemoteUser    string
+	Time          string
+	Request       string
+	Status        int
+	Bytes         string
+	Duration      int64
+	Headers       map[string]string
+}
+
+func logEntryFor(r *http.Request, sw *statusWriter, elapsed time.Duration, trustedProxies map[string]bool) logEntryData {
+	host := r.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	// Only a configured trusted proxy's RemoteAddr may override %h via
+	// X-Forwarded-For — otherwise any client could forge %h (and, unless
+	// sanitized, inject arbitrary fields into the log line).
+	if trustedProxies[host] {
+		if fwd, ok := firstForwardedIP(r.Header.Get("X-Forwarded-For")); ok {
+			host = fwd
+		}
+	}
+
+	user := "-"
+	if u, _, ok := r.BasicAuth(); ok {
+		user = u
+	}
+
+	bytes := "-"
+	if sw.bytes > 0 {
+		bytes = strconv.FormatInt(sw.bytes, 10)
+	}
+
+	return logEntryData{
+		Host:          host,
+		RemoteLogname: "-",
+		RemoteUser:    user,
+		Time:          time.Now().Format("[02/Jan/2006:15:04:05 -0700]"),
+		Request:       r.Method + " " + r.RequestURI + " " + r.Proto,
+		Status:        sw.status,
+		Bytes:         bytes,
+		Duration:      elapsed.Microseconds(),
+		Headers:       flattenHeaders(r.Header),
+	}
+}
+
+// firstForwardedIP returns the first comma-separated entry of an
+// X-Forwarded-For header that parses as a valid IP, rejecting the whole
+// value otherwise so a malformed or injected header can't end up as %h.
+func firstForwardedIP(fwd string) (string, bool) {
+	if fwd == "" {
+		return "", false
+	}
+	first := strings.TrimSpace(strings.SplitN(fwd, ",", 2)[0])
+	if net.ParseIP(first) == nil {
+		return "", false
+	}
+	return first, true
+}
+
+func flattenHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k := range h {
+		out[k] = h.Get(k)
+	}
+	return out
+}